@@ -0,0 +1,86 @@
+package data
+
+import (
+	"time"
+
+	"github.com/alfg/openencoder/api/types"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// APIKeyDatastore handles database operations for API keys.
+type APIKeyDatastore struct {
+	db *sqlx.DB
+}
+
+// CreateAPIKey inserts a new API key.
+func (d *APIKeyDatastore) CreateAPIKey(k *types.APIKey) error {
+	query := `INSERT INTO api_keys (id, secret_hash, owner, role, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := d.db.Exec(query, k.ID, k.SecretHash, k.Owner, k.Role, pq.Array(k.Scopes), k.ExpiresAt, k.CreatedAt)
+	return err
+}
+
+// GetAPIKeyByID returns the API key with the given public ID.
+func (d *APIKeyDatastore) GetAPIKeyByID(id string) (*types.APIKey, error) {
+	var k types.APIKey
+	var scopes pq.StringArray
+	err := d.db.QueryRowx(`SELECT id, secret_hash, owner, role, scopes, expires_at, last_used_at, created_at
+		FROM api_keys WHERE id=$1`, id).Scan(
+		&k.ID, &k.SecretHash, &k.Owner, &k.Role, &scopes, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = []string(scopes)
+	return &k, nil
+}
+
+// apiKeyRow mirrors types.APIKey but scans the "scopes" Postgres array
+// column into pq.StringArray, which implements sql.Scanner - []string on
+// its own doesn't.
+type apiKeyRow struct {
+	ID         string         `db:"id"`
+	SecretHash string         `db:"secret_hash"`
+	Owner      string         `db:"owner"`
+	Role       string         `db:"role"`
+	Scopes     pq.StringArray `db:"scopes"`
+	ExpiresAt  *time.Time     `db:"expires_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// ListAPIKeys returns every API key, most recently created first.
+func (d *APIKeyDatastore) ListAPIKeys() ([]*types.APIKey, error) {
+	var rows []apiKeyRow
+	if err := d.db.Select(&rows, `SELECT * FROM api_keys ORDER BY created_at DESC`); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*types.APIKey, len(rows))
+	for i, r := range rows {
+		keys[i] = &types.APIKey{
+			ID:         r.ID,
+			SecretHash: r.SecretHash,
+			Owner:      r.Owner,
+			Role:       r.Role,
+			Scopes:     []string(r.Scopes),
+			ExpiresAt:  r.ExpiresAt,
+			LastUsedAt: r.LastUsedAt,
+			CreatedAt:  r.CreatedAt,
+		}
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey deletes an API key, immediately invalidating it.
+func (d *APIKeyDatastore) RevokeAPIKey(id string) error {
+	_, err := d.db.Exec(`DELETE FROM api_keys WHERE id=$1`, id)
+	return err
+}
+
+// TouchAPIKeyLastUsed records that an API key was just used to authenticate
+// a request.
+func (d *APIKeyDatastore) TouchAPIKeyLastUsed(id string, at time.Time) error {
+	_, err := d.db.Exec(`UPDATE api_keys SET last_used_at=$1 WHERE id=$2`, at, id)
+	return err
+}
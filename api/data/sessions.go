@@ -0,0 +1,69 @@
+package data
+
+import (
+	"time"
+
+	"github.com/alfg/openencoder/api/types"
+	"github.com/jmoiron/sqlx"
+)
+
+// SessionDatastore handles database operations for sessions.
+type SessionDatastore struct {
+	db *sqlx.DB
+}
+
+// CreateSession inserts a new session and sets its generated ID on s.
+func (d *SessionDatastore) CreateSession(s *types.Session) error {
+	query := `INSERT INTO sessions (username, jti, secret_hash, user_agent, ip, issued_at, expires_at, revoked)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	return d.db.Get(&s.ID, query, s.Username, s.JTI, s.SecretHash, s.UserAgent, s.IP, s.IssuedAt, s.ExpiresAt, s.Revoked)
+}
+
+// GetSessionByID returns the session with the given ID.
+func (d *SessionDatastore) GetSessionByID(id int64) (*types.Session, error) {
+	var s types.Session
+	err := d.db.Get(&s, `SELECT * FROM sessions WHERE id=$1`, id)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetSessionByJTI returns the session that minted the access token with the
+// given jti claim.
+func (d *SessionDatastore) GetSessionByJTI(jti string) (*types.Session, error) {
+	var s types.Session
+	err := d.db.Get(&s, `SELECT * FROM sessions WHERE jti=$1`, jti)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListSessionsForUser returns every session belonging to username, most
+// recent first.
+func (d *SessionDatastore) ListSessionsForUser(username string) ([]*types.Session, error) {
+	var sessions []*types.Session
+	err := d.db.Select(&sessions, `SELECT * FROM sessions WHERE username=$1 ORDER BY issued_at DESC`, username)
+	return sessions, err
+}
+
+// RevokeSession marks a single session as revoked.
+func (d *SessionDatastore) RevokeSession(id int64) error {
+	_, err := d.db.Exec(`UPDATE sessions SET revoked=true WHERE id=$1`, id)
+	return err
+}
+
+// RevokeSessionsForUser revokes every session belonging to username. Used to
+// kick a compromised or deactivated user off of every device at once.
+func (d *SessionDatastore) RevokeSessionsForUser(username string) error {
+	_, err := d.db.Exec(`UPDATE sessions SET revoked=true WHERE username=$1`, username)
+	return err
+}
+
+// PruneExpiredSessions deletes sessions that expired before now, keeping the
+// table small. Intended to be called periodically from a housekeeping job.
+func (d *SessionDatastore) PruneExpiredSessions(now time.Time) error {
+	_, err := d.db.Exec(`DELETE FROM sessions WHERE expires_at < $1`, now)
+	return err
+}
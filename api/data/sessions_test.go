@@ -0,0 +1,85 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func newSessionDatastore(t *testing.T) (*SessionDatastore, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return &SessionDatastore{db: sqlx.NewDb(sqlDB, "sqlmock")}, mock
+}
+
+func TestGetSessionByJTI(t *testing.T) {
+	d, mock := newSessionDatastore(t)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "username", "jti", "secret_hash", "user_agent", "ip", "issued_at", "expires_at", "revoked"}).
+		AddRow(1, "alice", "jti-123", "hash", "curl", "127.0.0.1", now, now.Add(time.Hour), false)
+	mock.ExpectQuery("SELECT \\* FROM sessions WHERE jti=\\$1").
+		WithArgs("jti-123").
+		WillReturnRows(rows)
+
+	session, err := d.GetSessionByJTI("jti-123")
+	if err != nil {
+		t.Fatalf("GetSessionByJTI: %v", err)
+	}
+	if session.Username != "alice" || session.Revoked {
+		t.Errorf("unexpected session: %+v", session)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	d, mock := newSessionDatastore(t)
+
+	mock.ExpectExec("UPDATE sessions SET revoked=true WHERE id=\\$1").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := d.RevokeSession(1); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestListSessionsForUser(t *testing.T) {
+	d, mock := newSessionDatastore(t)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "username", "jti", "secret_hash", "user_agent", "ip", "issued_at", "expires_at", "revoked"}).
+		AddRow(1, "alice", "jti-1", "hash", "curl", "127.0.0.1", now, now.Add(time.Hour), false).
+		AddRow(2, "alice", "jti-2", "hash", "curl", "127.0.0.1", now, now.Add(time.Hour), true)
+	mock.ExpectQuery("SELECT \\* FROM sessions WHERE username=\\$1 ORDER BY issued_at DESC").
+		WithArgs("alice").
+		WillReturnRows(rows)
+
+	sessions, err := d.ListSessionsForUser("alice")
+	if err != nil {
+		t.Fatalf("ListSessionsForUser: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("len(sessions) = %d, want 2", len(sessions))
+	}
+	if !sessions[1].Revoked {
+		t.Errorf("sessions[1].Revoked = false, want true")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package machine
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/digitalocean/godo"
+)
+
+// DigitalOceanProvider implements Provider on top of the DigitalOcean API,
+// the original (pre-Provider-interface) backend for this package. Droplet
+// IDs are numeric; Machine.ID stores them stringified via strconv so the
+// type is shared with providers (Docker) whose IDs are hex strings.
+type DigitalOceanProvider struct {
+	client *godo.Client
+}
+
+// NewDigitalOceanProvider builds a provider authenticated with token.
+func NewDigitalOceanProvider(token string) *DigitalOceanProvider {
+	return &DigitalOceanProvider{client: godo.NewFromToken(token)}
+}
+
+// Create launches a droplet per spec.
+func (p *DigitalOceanProvider) Create(ctx context.Context, spec Spec) (Machine, error) {
+	droplet, _, err := p.client.Droplets.Create(ctx, &godo.DropletCreateRequest{
+		Name:   spec.Name,
+		Region: spec.Region,
+		Size:   spec.SizeSlug,
+		Tags:   spec.Tags,
+		Image:  godo.DropletCreateImage{Slug: "docker-20-04"},
+	})
+	if err != nil {
+		return Machine{}, err
+	}
+
+	return Machine{
+		ID:       strconv.Itoa(droplet.ID),
+		Name:     droplet.Name,
+		Status:   droplet.Status,
+		SizeSlug: droplet.SizeSlug,
+		Region:   droplet.Region.Slug,
+		Tags:     droplet.Tags,
+		Provider: "digitalocean",
+	}, nil
+}
+
+// Delete destroys the droplet identified by id (a stringified droplet ID).
+func (p *DigitalOceanProvider) Delete(ctx context.Context, id string) error {
+	dropletID, err := strconv.Atoi(id)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.Droplets.Delete(ctx, dropletID)
+	return err
+}
+
+// List returns every droplet on the account.
+func (p *DigitalOceanProvider) List(ctx context.Context) ([]Machine, error) {
+	droplets, _, err := p.client.Droplets.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	machines := make([]Machine, 0, len(droplets))
+	for _, d := range droplets {
+		machines = append(machines, Machine{
+			ID:       strconv.Itoa(d.ID),
+			Name:     d.Name,
+			Status:   d.Status,
+			SizeSlug: d.SizeSlug,
+			Region:   d.Region.Slug,
+			Tags:     d.Tags,
+			Provider: "digitalocean",
+		})
+	}
+	return machines, nil
+}
+
+// Status returns the droplet's current state (e.g. "active", "off").
+func (p *DigitalOceanProvider) Status(ctx context.Context, id string) (string, error) {
+	dropletID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", err
+	}
+	droplet, _, err := p.client.Droplets.Get(ctx, dropletID)
+	if err != nil {
+		return "", err
+	}
+	return droplet.Status, nil
+}
@@ -0,0 +1,177 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// DockerSpec is the Docker-specific half of Spec, mirroring the richer
+// container-run parameters common to worker-orchestration systems so a
+// deployment can run encode workers without a cloud account.
+type DockerSpec struct {
+	Image         string
+	Env           []string
+	CPUShares     int64
+	Memory        int64 // bytes
+	MemorySwap    int64 // bytes, -1 for unlimited
+	RestartPolicy string
+	DNS           []string
+	Mounts        []DockerMount
+	Ports         []DockerPort
+}
+
+// DockerMount binds a host path into the container. Every mount is created
+// as a bind mount, so Source is a host path; named volumes are not supported.
+type DockerMount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// DockerPort publishes a container port on the host.
+type DockerPort struct {
+	HostPort      string
+	ContainerPort string
+	Protocol      string // "tcp" or "udp", defaults to "tcp"
+}
+
+// DockerProvider implements Provider on top of the Docker Engine API,
+// launching worker containers locally or on a remote docker host.
+type DockerProvider struct {
+	client *client.Client
+}
+
+// NewDockerProvider connects to the Docker daemon. host may be empty to use
+// the default local socket, or a remote docker host (e.g. "tcp://1.2.3.4:2376").
+func NewDockerProvider(host string) (*DockerProvider, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+
+	c, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &DockerProvider{client: c}, nil
+}
+
+// Create launches a worker container per spec.Docker.
+func (p *DockerProvider) Create(ctx context.Context, spec Spec) (Machine, error) {
+	if spec.Docker == nil {
+		return Machine{}, fmt.Errorf("docker: spec.Docker is required")
+	}
+	d := spec.Docker
+
+	portBindings := nat.PortMap{}
+	exposedPorts := nat.PortSet{}
+	for _, port := range d.Ports {
+		proto := port.Protocol
+		if proto == "" {
+			proto = "tcp"
+		}
+		containerPort, err := nat.NewPort(proto, port.ContainerPort)
+		if err != nil {
+			return Machine{}, err
+		}
+		exposedPorts[containerPort] = struct{}{}
+		portBindings[containerPort] = []nat.PortBinding{{HostPort: port.HostPort}}
+	}
+
+	var mounts []mount.Mount
+	for _, m := range d.Mounts {
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   m.Source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	restartPolicy := container.RestartPolicy{}
+	if d.RestartPolicy != "" {
+		restartPolicy.Name = d.RestartPolicy
+	}
+
+	resp, err := p.client.ContainerCreate(ctx, &container.Config{
+		Image:        d.Image,
+		Env:          d.Env,
+		Labels:       map[string]string{"openencoder.machine.name": spec.Name},
+		ExposedPorts: exposedPorts,
+	}, &container.HostConfig{
+		PortBindings:  portBindings,
+		Mounts:        mounts,
+		DNS:           d.DNS,
+		RestartPolicy: restartPolicy,
+		Resources: container.Resources{
+			CPUShares:  d.CPUShares,
+			Memory:     d.Memory,
+			MemorySwap: d.MemorySwap,
+		},
+	}, nil, nil, spec.Name)
+	if err != nil {
+		return Machine{}, err
+	}
+
+	if err := p.client.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return Machine{}, err
+	}
+
+	return Machine{
+		ID:       resp.ID,
+		Name:     spec.Name,
+		Status:   "created",
+		Tags:     spec.Tags,
+		Provider: "docker",
+	}, nil
+}
+
+// Delete stops and removes the container.
+func (p *DockerProvider) Delete(ctx context.Context, id string) error {
+	return p.client.ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true})
+}
+
+// List returns every container openencoder launched, identified by the
+// "openencoder.machine.name" label Create sets - without that filter this
+// would return every container on the host, including ones this service
+// never created and has no business deleting.
+func (p *DockerProvider) List(ctx context.Context) ([]Machine, error) {
+	containers, err := p.client.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", "openencoder.machine.name")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	machines := make([]Machine, 0, len(containers))
+	for _, ctr := range containers {
+		name := ctr.ID
+		if len(ctr.Names) > 0 {
+			name = ctr.Names[0]
+		}
+		machines = append(machines, Machine{
+			ID:       ctr.ID,
+			Name:     name,
+			Status:   ctr.Status,
+			Provider: "docker",
+		})
+	}
+	return machines, nil
+}
+
+// Status returns the container's current state (e.g. "running", "exited").
+func (p *DockerProvider) Status(ctx context.Context, id string) (string, error) {
+	inspect, err := p.client.ContainerInspect(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return inspect.State.Status, nil
+}
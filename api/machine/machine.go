@@ -1,8 +1,12 @@
 package machine
 
-// Machine defines a machine struct from a provider.
+import "context"
+
+// Machine defines a machine struct from a provider. ID is a string so it
+// can hold both numeric cloud-provider IDs (stringified) and Docker's
+// hex container IDs.
 type Machine struct {
-	ID       int      `json:"id"`
+	ID       string   `json:"id"`
 	Name     string   `json:"name"`
 	Status   string   `json:"status"`
 	SizeSlug string   `json:"size_slug"`
@@ -15,12 +19,48 @@ type Machine struct {
 
 // MachineCreated defines the response for creating a machine.
 type MachineCreated struct {
-	ID       int    `json:"id"`
+	ID       string `json:"id"`
 	Provider string `json:"provider"`
 }
 
 // MachineDeleted defines the response for deleted a machine.
 type MachineDeleted struct {
-	ID       int    `json:"id"`
+	ID       string `json:"id"`
 	Provider string `json:"provider"`
 }
+
+// Spec describes the machine to create. Region/SizeSlug are only
+// meaningful to cloud providers; Docker is only read by the docker
+// provider. A provider ignores whichever fields don't apply to it.
+type Spec struct {
+	Name     string
+	Region   string
+	SizeSlug string
+	Tags     []string
+	Docker   *DockerSpec
+}
+
+// Provider abstracts a backend capable of creating and managing worker
+// machines, whether that's a cloud VM or a local/remote Docker container.
+// Each provider is registered under a name via Register so callers (and
+// the admin UI) can pick one per request the same way across backends.
+type Provider interface {
+	Create(ctx context.Context, spec Spec) (Machine, error)
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]Machine, error)
+	Status(ctx context.Context, id string) (string, error)
+}
+
+var providers = map[string]Provider{}
+
+// Register adds a provider to the registry under name, e.g. "digitalocean"
+// or "docker". Intended to be called once at startup.
+func Register(name string, p Provider) {
+	providers[name] = p
+}
+
+// Get returns the provider registered under name, if any.
+func Get(name string) (Provider, bool) {
+	p, ok := providers[name]
+	return p, ok
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/hex"
+	"time"
+
+	"github.com/alfg/openencoder/api/data"
+	"github.com/alfg/openencoder/api/helpers"
+	"github.com/alfg/openencoder/api/types"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type createAPIKeyRequest struct {
+	Owner     string     `json:"owner" binding:"required"`
+	Role      string     `json:"role" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// createAPIKeyHandler mints a new API key for owner and returns the raw
+// "<id>.<secret>" value once - only its bcrypt hash is kept server-side.
+func createAPIKeyHandler(c *gin.Context) {
+	user, ok := c.Get(identityKey)
+	if !ok || !isAdmin(user) {
+		c.JSON(403, gin.H{"code": 403, "message": "admin role required"})
+		return
+	}
+
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+
+	id := hex.EncodeToString(helpers.GenerateRandomKey(8))
+	secret := hex.EncodeToString(helpers.GenerateRandomKey(32))
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not create api key"})
+		return
+	}
+
+	key := &types.APIKey{
+		ID:         id,
+		SecretHash: string(hash),
+		Owner:      req.Owner,
+		Role:       req.Role,
+		Scopes:     req.Scopes,
+		ExpiresAt:  req.ExpiresAt,
+		CreatedAt:  time.Now(),
+	}
+	if err := data.New().APIKeys.CreateAPIKey(key); err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not create api key"})
+		return
+	}
+
+	c.JSON(201, gin.H{
+		"id":      key.ID,
+		"api_key": id + "." + secret,
+		"owner":   key.Owner,
+		"role":    key.Role,
+		"scopes":  key.Scopes,
+	})
+}
+
+// listAPIKeysHandler returns every API key, without secrets.
+func listAPIKeysHandler(c *gin.Context) {
+	user, ok := c.Get(identityKey)
+	if !ok || !isAdmin(user) {
+		c.JSON(403, gin.H{"code": 403, "message": "admin role required"})
+		return
+	}
+
+	keys, err := data.New().APIKeys.ListAPIKeys()
+	if err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not list api keys"})
+		return
+	}
+	c.JSON(200, keys)
+}
+
+// revokeAPIKeyHandler deletes the API key identified by :id.
+func revokeAPIKeyHandler(c *gin.Context) {
+	user, ok := c.Get(identityKey)
+	if !ok || !isAdmin(user) {
+		c.JSON(403, gin.H{"code": 403, "message": "admin role required"})
+		return
+	}
+
+	if err := data.New().APIKeys.RevokeAPIKey(c.Param("id")); err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not revoke api key"})
+		return
+	}
+	c.JSON(200, gin.H{"code": 200, "message": "api key revoked"})
+}
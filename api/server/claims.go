@@ -0,0 +1,24 @@
+package server
+
+import jwt "github.com/appleboy/gin-jwt/v2"
+
+// stringClaim reads a string claim out of claims, returning "" if it's
+// absent or not a string. Tokens minted for a narrower purpose than a
+// normal login - e.g. the password-reset token, which only carries "id",
+// "aud" and "exp" - don't have every claim IdentityHandler would otherwise
+// expect, so callers must tolerate a miss here instead of panicking on a
+// failed type assertion. The narrower token is still rejected later, by
+// Authorizator checking its "aud".
+func stringClaim(claims jwt.MapClaims, key string) string {
+	v, ok := claims[key].(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+// isPasswordResetClaims reports whether claims belong to a password-reset
+// token rather than a normal access token.
+func isPasswordResetClaims(claims jwt.MapClaims) bool {
+	return stringClaim(claims, audKey) == passwordResetAudience
+}
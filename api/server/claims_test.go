@@ -0,0 +1,40 @@
+package server
+
+import (
+	"testing"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+)
+
+func TestStringClaimMissing(t *testing.T) {
+	claims := jwt.MapClaims{"id": "alice"}
+
+	if got := stringClaim(claims, "id"); got != "alice" {
+		t.Errorf("stringClaim(id) = %q, want %q", got, "alice")
+	}
+
+	// A password-reset token has no "role" claim - this must not panic.
+	if got := stringClaim(claims, "role"); got != "" {
+		t.Errorf("stringClaim(role) = %q, want empty string", got)
+	}
+}
+
+func TestIsPasswordResetClaims(t *testing.T) {
+	cases := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   bool
+	}{
+		{"reset token", jwt.MapClaims{"id": "alice", "aud": passwordResetAudience}, true},
+		{"normal access token", jwt.MapClaims{"id": "alice", "role": "admin", "jti": "abc"}, false},
+		{"no aud claim", jwt.MapClaims{"id": "alice"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isPasswordResetClaims(c.claims); got != c.want {
+				t.Errorf("isPasswordResetClaims() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,9 @@
 package server
 
 import (
-	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/alfg/openencoder/api/config"
@@ -24,12 +25,23 @@ const (
 	realm       = "openencoder"
 	identityKey = "id"
 	roleKey     = "role"
-	timeout     = time.Hour // Duration a JWT is valid.
-	maxRefresh  = time.Hour // Duration a JWT can be refreshed.
+	jtiKey      = "jti"
+	audKey      = "aud"
+	timeout     = 15 * time.Minute // Duration an access JWT is valid.
+	maxRefresh  = time.Hour        // Duration a JWT can be refreshed.
 )
 
 var jwtKey []byte
 
+// sessionUser carries the JTI of the access token being minted for a login
+// alongside the user, so PayloadFunc can bind the token to its backing
+// data.Sessions row without changing what IdentityHandler hands back to the
+// rest of the app.
+type sessionUser struct {
+	*types.User
+	JTI string
+}
+
 func jwtMiddleware() *jwt.GinJWTMiddleware {
 
 	// Set the JWT Key if provided in config. Otherwise, generate a random one.
@@ -48,6 +60,13 @@ func jwtMiddleware() *jwt.GinJWTMiddleware {
 		IdentityKey: identityKey,
 
 		PayloadFunc: func(data interface{}) jwt.MapClaims {
+			if v, ok := data.(*sessionUser); ok {
+				return jwt.MapClaims{
+					identityKey: v.Username,
+					roleKey:     v.Role,
+					jtiKey:      v.JTI,
+				}
+			}
 			if v, ok := data.(*types.User); ok {
 				return jwt.MapClaims{
 					identityKey: v.Username,
@@ -60,8 +79,8 @@ func jwtMiddleware() *jwt.GinJWTMiddleware {
 		IdentityHandler: func(c *gin.Context) interface{} {
 			claims := jwt.ExtractClaims(c)
 			return &types.User{
-				Username: claims["id"].(string),
-				Role:     claims["role"].(string),
+				Username: stringClaim(claims, identityKey),
+				Role:     stringClaim(claims, roleKey),
 			}
 		},
 
@@ -85,28 +104,65 @@ func jwtMiddleware() *jwt.GinJWTMiddleware {
 				return nil, jwt.ErrFailedAuthentication
 			}
 
-			// Error with 403 if password needs to be reset.
+			// Instead of a dead-end 403, mint a short-lived password-reset
+			// token and surface it through Unauthorized so the client has a
+			// path forward: POST /auth/password/reset.
 			if user.ForcePasswordReset {
-				return nil, errors.New("require password reset")
+				resetToken, err := mintPasswordResetToken(user.Username)
+				if err != nil {
+					return nil, jwt.ErrFailedAuthentication
+				}
+				return nil, fmt.Errorf("%s%s", passwordResetErrPrefix, resetToken)
 			}
 
-			// Log-in the user.
-			return &types.User{
-				Username: user.Username,
-				Role:     user.Role,
-			}, nil
+			// Open a new revocable session and bind the access token about to
+			// be minted to it via jti, stashing the opaque refresh token on the
+			// context for LoginResponse to hand back alongside the JWT.
+			su, refreshToken, err := startSession(c, user)
+			if err != nil {
+				return nil, jwt.ErrFailedAuthentication
+			}
+			c.Set("refresh_token", refreshToken)
+			return su, nil
 		},
 
 		Authorizator: func(data interface{}, c *gin.Context) bool {
-			// Only authorize if user has the following roles.
-			if v, ok := data.(*types.User); ok &&
-				(v.Role == "guest" || v.Role == "operator" || v.Role == "admin") {
+			v, ok := data.(*types.User)
+			if !ok || (v.Role != "guest" && v.Role != "operator" && v.Role != "admin") {
+				return false
+			}
+
+			// Reject tokens whose session has been revoked, so an admin can
+			// immediately kick a compromised user off even before its JWT
+			// expires naturally.
+			claims := jwt.ExtractClaims(c)
+
+			// Password-reset tokens carry a distinct audience and must never
+			// authorize against any other route.
+			if isPasswordResetClaims(claims) {
+				return false
+			}
+
+			jti, _ := claims[jtiKey].(string)
+			if jti == "" {
 				return true
 			}
-			return false
+			session, err := data.New().Sessions.GetSessionByJTI(jti)
+			if err != nil || session.Revoked {
+				return false
+			}
+			return true
 		},
 
 		Unauthorized: func(c *gin.Context, code int, message string) {
+			if strings.HasPrefix(message, passwordResetErrPrefix) {
+				c.JSON(428, gin.H{
+					"code":        428,
+					"reason":      "password_reset_required",
+					"reset_token": strings.TrimPrefix(message, passwordResetErrPrefix),
+				})
+				return
+			}
 			c.JSON(code, gin.H{
 				"code":    code,
 				"message": message,
@@ -114,11 +170,8 @@ func jwtMiddleware() *jwt.GinJWTMiddleware {
 		},
 
 		LoginResponse: func(c *gin.Context, code int, message string, time time.Time) {
-			c.JSON(code, gin.H{
-				"code":   code,
-				"token":  message,
-				"expire": time,
-			})
+			refreshToken, _ := c.Get("refresh_token")
+			respondWithToken(c, code, message, time, refreshToken)
 		},
 
 		TokenLookup:   "header: Authorization, query: token, cookie: jwt",
@@ -132,6 +185,23 @@ func jwtMiddleware() *jwt.GinJWTMiddleware {
 	return authMiddleware
 }
 
+// respondWithToken writes the standard login JSON response. It is shared by
+// gin-jwt's LoginResponse callback, the OAuth callback handler and
+// /auth/refresh so every auth path hands back an identical payload.
+// refreshToken is omitted when nil, e.g. when refreshing an access token
+// doesn't rotate the underlying session's opaque refresh token.
+func respondWithToken(c *gin.Context, code int, token string, expire time.Time, refreshToken interface{}) {
+	body := gin.H{
+		"code":   code,
+		"token":  token,
+		"expire": expire,
+	}
+	if refreshToken != nil {
+		body["refresh_token"] = refreshToken
+	}
+	c.JSON(code, body)
+}
+
 // User role types.
 const (
 	admin    = "admin"
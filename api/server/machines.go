@@ -0,0 +1,29 @@
+package server
+
+import (
+	"log"
+
+	"github.com/alfg/openencoder/api/config"
+	"github.com/alfg/openencoder/api/machine"
+)
+
+// initMachineProviders builds and registers the configured machine
+// providers, mirroring initOAuthProviders: a no-op for any provider
+// without its connection details configured, so a deployment that only
+// wants one backend pays no cost for the other.
+func initMachineProviders() {
+	c := config.Get()
+
+	if c.Docker.Enabled {
+		p, err := machine.NewDockerProvider(c.Docker.Host)
+		if err != nil {
+			log.Printf("machine: could not connect to docker, provider disabled: %v", err)
+		} else {
+			machine.Register("docker", p)
+		}
+	}
+
+	if c.DigitalOcean.Token != "" {
+		machine.Register("digitalocean", machine.NewDigitalOceanProvider(c.DigitalOcean.Token))
+	}
+}
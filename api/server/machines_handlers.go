@@ -0,0 +1,79 @@
+package server
+
+import (
+	"github.com/alfg/openencoder/api/machine"
+	"github.com/gin-gonic/gin"
+)
+
+type createMachineRequest struct {
+	Provider string              `json:"provider" binding:"required"`
+	Name     string              `json:"name" binding:"required"`
+	Region   string              `json:"region"`
+	SizeSlug string              `json:"size_slug"`
+	Tags     []string            `json:"tags"`
+	Docker   *machine.DockerSpec `json:"docker"`
+}
+
+// createMachineHandler creates a worker machine on the backend named by the
+// request body's "provider" field.
+func createMachineHandler(c *gin.Context) {
+	var req createMachineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+
+	p, ok := machine.Get(req.Provider)
+	if !ok {
+		c.JSON(404, gin.H{"code": 404, "message": "unknown machine provider: " + req.Provider})
+		return
+	}
+
+	m, err := p.Create(c.Request.Context(), machine.Spec{
+		Name:     req.Name,
+		Region:   req.Region,
+		SizeSlug: req.SizeSlug,
+		Tags:     req.Tags,
+		Docker:   req.Docker,
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not create machine"})
+		return
+	}
+	c.JSON(201, machine.MachineCreated{ID: m.ID, Provider: req.Provider})
+}
+
+// listMachinesHandler lists every machine running on :provider's backend.
+func listMachinesHandler(c *gin.Context) {
+	name := c.Param("provider")
+	p, ok := machine.Get(name)
+	if !ok {
+		c.JSON(404, gin.H{"code": 404, "message": "unknown machine provider: " + name})
+		return
+	}
+
+	machines, err := p.List(c.Request.Context())
+	if err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not list machines"})
+		return
+	}
+	c.JSON(200, machines)
+}
+
+// deleteMachineHandler tears down the machine identified by :id on
+// :provider's backend.
+func deleteMachineHandler(c *gin.Context) {
+	name := c.Param("provider")
+	p, ok := machine.Get(name)
+	if !ok {
+		c.JSON(404, gin.H{"code": 404, "message": "unknown machine provider: " + name})
+		return
+	}
+
+	id := c.Param("id")
+	if err := p.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not delete machine"})
+		return
+	}
+	c.JSON(200, machine.MachineDeleted{ID: id, Provider: name})
+}
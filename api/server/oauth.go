@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/alfg/openencoder/api/config"
+	"github.com/alfg/openencoder/api/data"
+	"github.com/alfg/openencoder/api/types"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+)
+
+// OAuthIdentity is the normalized identity returned by an OAuthProvider
+// once a callback code has been exchanged for the remote user's profile.
+type OAuthIdentity struct {
+	ProviderUserID string
+	Username       string
+}
+
+// OAuthProvider abstracts an external identity provider (Google, GitHub or
+// a generic OIDC issuer) so the oauth callback route can treat all of them
+// the same way.
+type OAuthProvider interface {
+	Name() string
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*OAuthIdentity, error)
+}
+
+// oauthProviders holds the configured providers, keyed by the name used in
+// the `/auth/oauth/:provider/callback` route.
+var oauthProviders = map[string]OAuthProvider{}
+
+// registerOAuthProvider adds a provider to the registry. Providers are built
+// from config.Get() once at startup, by initOAuthProviders.
+func registerOAuthProvider(p OAuthProvider) {
+	oauthProviders[p.Name()] = p
+}
+
+// initOAuthProviders builds the configured OAuth providers from config.
+// It is a no-op for any provider without a client ID configured, so
+// deployments that don't use SSO pay no cost.
+func initOAuthProviders() {
+	c := config.Get()
+
+	if c.OAuth.Google.ClientID != "" {
+		registerOAuthProvider(&genericOAuthProvider{
+			name:        "google",
+			userinfoURL: userinfoURLs["google"],
+			conf: &oauth2.Config{
+				ClientID:     c.OAuth.Google.ClientID,
+				ClientSecret: c.OAuth.Google.ClientSecret,
+				RedirectURL:  c.OAuth.Google.RedirectURL,
+				Scopes:       []string{"openid", "email", "profile"},
+				Endpoint:     google.Endpoint,
+			},
+		})
+	}
+
+	if c.OAuth.GitHub.ClientID != "" {
+		registerOAuthProvider(&genericOAuthProvider{
+			name:        "github",
+			userinfoURL: userinfoURLs["github"],
+			conf: &oauth2.Config{
+				ClientID:     c.OAuth.GitHub.ClientID,
+				ClientSecret: c.OAuth.GitHub.ClientSecret,
+				RedirectURL:  c.OAuth.GitHub.RedirectURL,
+				Scopes:       []string{"read:user", "user:email"},
+				Endpoint:     github.Endpoint,
+			},
+		})
+	}
+
+	if c.OAuth.OIDC.ClientID != "" {
+		registerOAuthProvider(&genericOAuthProvider{
+			name:        "oidc",
+			userinfoURL: c.OAuth.OIDC.UserinfoURL,
+			conf: &oauth2.Config{
+				ClientID:     c.OAuth.OIDC.ClientID,
+				ClientSecret: c.OAuth.OIDC.ClientSecret,
+				RedirectURL:  c.OAuth.OIDC.RedirectURL,
+				Scopes:       c.OAuth.OIDC.Scopes,
+				Endpoint: oauth2.Endpoint{
+					AuthURL:  c.OAuth.OIDC.AuthURL,
+					TokenURL: c.OAuth.OIDC.TokenURL,
+				},
+			},
+		})
+	}
+}
+
+// genericOAuthProvider implements OAuthProvider on top of golang.org/x/oauth2
+// and is reused for Google, GitHub and generic OIDC issuers since they all
+// share the same authorization-code flow.
+type genericOAuthProvider struct {
+	name        string
+	userinfoURL string
+	conf        *oauth2.Config
+}
+
+func (p *genericOAuthProvider) Name() string {
+	return p.name
+}
+
+func (p *genericOAuthProvider) AuthCodeURL(state string) string {
+	return p.conf.AuthCodeURL(state)
+}
+
+func (p *genericOAuthProvider) Exchange(ctx context.Context, code string) (*OAuthIdentity, error) {
+	token, err := p.conf.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteID, err := fetchOAuthUserinfo(ctx, p.conf, token, p.userinfoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthIdentity{
+		ProviderUserID: remoteID,
+		Username:       fmt.Sprintf("%s:%s", p.name, remoteID),
+	}, nil
+}
+
+// oauthRedirectHandler starts the login-CSRF-protected flow by minting a
+// state nonce bound to this provider and redirecting the browser to the
+// provider's consent screen.
+func oauthRedirectHandler(c *gin.Context) {
+	name := c.Param("provider")
+	provider, ok := oauthProviders[name]
+	if !ok {
+		c.JSON(404, gin.H{"code": 404, "message": "unknown oauth provider"})
+		return
+	}
+
+	state, nonce, err := mintOAuthState(name)
+	if err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not start oauth flow"})
+		return
+	}
+
+	secure := c.Request.TLS != nil
+	c.SetCookie(oauthStateCookieName, nonce, int(oauthStateTTL.Seconds()), "/", "", secure, true)
+	c.Redirect(302, provider.AuthCodeURL(state))
+}
+
+// oauthCallbackHandler resolves the remote identity for the `:provider`
+// route param, upserts a matching local user and issues the same JWT the
+// username/password login route returns.
+func oauthCallbackHandler(authMiddleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("provider")
+		provider, ok := oauthProviders[name]
+		if !ok {
+			c.JSON(404, gin.H{"code": 404, "message": "unknown oauth provider"})
+			return
+		}
+
+		code := c.Query("code")
+		if code == "" {
+			c.JSON(400, gin.H{"code": 400, "message": "missing code"})
+			return
+		}
+
+		// Verify the state nonce minted by oauthRedirectHandler, and that it
+		// matches the cookie set on this same browser, before doing anything
+		// with the code - so an attacker can't complete their own
+		// authorization flow and trick a victim's browser into completing it
+		// for them (login CSRF). A validly-signed state alone isn't enough:
+		// it has to have been handed to *this* browser.
+		cookieNonce, _ := c.Cookie(oauthStateCookieName)
+		secure := c.Request.TLS != nil
+		c.SetCookie(oauthStateCookieName, "", -1, "/", "", secure, true)
+		if err := verifyOAuthState(c.Query("state"), name, cookieNonce); err != nil {
+			c.JSON(401, gin.H{"code": 401, "message": "invalid oauth state"})
+			return
+		}
+
+		identity, err := provider.Exchange(c.Request.Context(), code)
+		if err != nil {
+			c.JSON(401, gin.H{"code": 401, "message": "oauth exchange failed"})
+			return
+		}
+
+		user, err := upsertOAuthUser(identity)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not provision user"})
+			return
+		}
+
+		su, refreshToken, err := startSession(c, user)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not start session"})
+			return
+		}
+
+		token, expire, err := authMiddleware.TokenGenerator(su)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not issue token"})
+			return
+		}
+		respondWithToken(c, 200, token, expire, refreshToken)
+	}
+}
+
+// upsertOAuthUser finds the local user matching an OAuth identity, creating
+// one with the default "guest" role on first sign-in. A lookup failure
+// other than "no such user" is returned as-is instead of being treated as
+// "doesn't exist yet", so a transient DB error can't cause a duplicate
+// user to be created.
+func upsertOAuthUser(identity *OAuthIdentity) (*types.User, error) {
+	db := data.New()
+
+	user, err := db.Users.GetUserByUsername(identity.Username)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	user = &types.User{
+		Username: identity.Username,
+		Role:     guest,
+	}
+	if err := db.Users.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,61 @@
+package server
+
+import "testing"
+
+func TestOAuthStateRoundTrip(t *testing.T) {
+	jwtKey = []byte("test-key")
+
+	state, nonce, err := mintOAuthState("google")
+	if err != nil {
+		t.Fatalf("mintOAuthState: %v", err)
+	}
+
+	if err := verifyOAuthState(state, "google", nonce); err != nil {
+		t.Errorf("verifyOAuthState() = %v, want nil", err)
+	}
+}
+
+func TestOAuthStateRejectsWrongProvider(t *testing.T) {
+	jwtKey = []byte("test-key")
+
+	state, nonce, err := mintOAuthState("google")
+	if err != nil {
+		t.Fatalf("mintOAuthState: %v", err)
+	}
+
+	if err := verifyOAuthState(state, "github", nonce); err == nil {
+		t.Error("verifyOAuthState() = nil, want error for mismatched provider")
+	}
+}
+
+func TestOAuthStateRejectsEmpty(t *testing.T) {
+	if err := verifyOAuthState("", "google", "some-nonce"); err == nil {
+		t.Error("verifyOAuthState(\"\") = nil, want error")
+	}
+}
+
+func TestOAuthStateRejectsMismatchedCookie(t *testing.T) {
+	jwtKey = []byte("test-key")
+
+	state, _, err := mintOAuthState("google")
+	if err != nil {
+		t.Fatalf("mintOAuthState: %v", err)
+	}
+
+	if err := verifyOAuthState(state, "google", "not-the-real-nonce"); err == nil {
+		t.Error("verifyOAuthState() = nil, want error for mismatched cookie nonce")
+	}
+}
+
+func TestOAuthStateRejectsMissingCookie(t *testing.T) {
+	jwtKey = []byte("test-key")
+
+	state, _, err := mintOAuthState("google")
+	if err != nil {
+		t.Fatalf("mintOAuthState: %v", err)
+	}
+
+	if err := verifyOAuthState(state, "google", ""); err == nil {
+		t.Error("verifyOAuthState() = nil, want error for missing cookie nonce")
+	}
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alfg/openencoder/api/data"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	jwtlib "github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordResetAudience marks a token as single-purpose: only good for
+// POST /auth/password/reset, never as a normal access token.
+const passwordResetAudience = "password_reset"
+
+// passwordResetErrPrefix is stashed in the error Authenticator returns so
+// the Unauthorized callback can tell a forced-reset case apart from a
+// regular failed login and respond with the reset token instead of a
+// generic message.
+const passwordResetErrPrefix = "password_reset_required:"
+
+// passwordResetTokenTTL is how long the reset token stays valid for.
+const passwordResetTokenTTL = 10 * time.Minute
+
+// mintPasswordResetToken signs a short-lived token scoped to resetting
+// username's password, using the same key as ordinary access JWTs.
+func mintPasswordResetToken(username string) (string, error) {
+	claims := jwtlib.MapClaims{
+		identityKey: username,
+		audKey:      passwordResetAudience,
+		"exp":       time.Now().Add(passwordResetTokenTTL).Unix(),
+	}
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims)
+	return token.SignedString(jwtKey)
+}
+
+// parsePasswordResetToken validates a reset token and returns the username
+// it was issued for.
+func parsePasswordResetToken(raw string) (string, error) {
+	token, err := jwtlib.Parse(raw, func(t *jwtlib.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwtlib.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtKey, nil
+	})
+	if err != nil || !token.Valid {
+		return "", errors.New("invalid or expired reset token")
+	}
+
+	claims, ok := token.Claims.(jwtlib.MapClaims)
+	if !ok {
+		return "", errors.New("invalid reset token")
+	}
+	if aud, _ := claims[audKey].(string); aud != passwordResetAudience {
+		return "", errors.New("token is not a password-reset token")
+	}
+	username, _ := claims[identityKey].(string)
+	if username == "" {
+		return "", errors.New("invalid reset token")
+	}
+	return username, nil
+}
+
+type resetPasswordRequest struct {
+	ResetToken  string `json:"reset_token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// resetPasswordHandler validates a password-reset token, sets the new
+// password, clears ForcePasswordReset and then logs the user in, returning
+// a normal access JWT just like /auth/login would.
+func resetPasswordHandler(authMiddleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req resetPasswordRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"code": 400, "message": err.Error()})
+			return
+		}
+
+		username, err := parsePasswordResetToken(req.ResetToken)
+		if err != nil {
+			c.JSON(401, gin.H{"code": 401, "message": err.Error()})
+			return
+		}
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not set new password"})
+			return
+		}
+
+		db := data.New()
+		if err := db.Users.ResetPassword(username, string(hash)); err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not set new password"})
+			return
+		}
+
+		user, err := db.Users.GetUserByUsername(username)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not log in after reset"})
+			return
+		}
+
+		su, refreshToken, err := startSession(c, user)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not start session"})
+			return
+		}
+		token, expire, err := authMiddleware.TokenGenerator(su)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not issue token"})
+			return
+		}
+		respondWithToken(c, 200, token, expire, refreshToken)
+	}
+}
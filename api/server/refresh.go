@@ -0,0 +1,95 @@
+package server
+
+import (
+	"github.com/alfg/openencoder/api/data"
+	"github.com/alfg/openencoder/api/types"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// refreshHandler exchanges a still-valid refresh token for a new access JWT.
+// The new JWT carries the same jti as the session's other access tokens, so
+// revoking the session invalidates it too.
+func refreshHandler(authMiddleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req refreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"code": 400, "message": "missing refresh_token"})
+			return
+		}
+
+		session, err := resolveRefreshToken(req.RefreshToken)
+		if err != nil {
+			c.JSON(401, gin.H{"code": 401, "message": err.Error()})
+			return
+		}
+
+		db := data.New()
+		user, err := db.Users.GetUserByUsername(session.Username)
+		if err != nil {
+			c.JSON(401, gin.H{"code": 401, "message": "unknown user"})
+			return
+		}
+
+		su := &sessionUser{
+			User: &types.User{Username: user.Username, Role: user.Role},
+			JTI:  session.JTI,
+		}
+		token, expire, err := authMiddleware.TokenGenerator(su)
+		if err != nil {
+			c.JSON(500, gin.H{"code": 500, "message": "could not issue token"})
+			return
+		}
+		respondWithToken(c, 200, token, expire, nil)
+	}
+}
+
+// logoutHandler revokes the session backing the caller's current access
+// token. Must run behind authMiddleware.MiddlewareFunc() so claims are set.
+func logoutHandler(c *gin.Context) {
+	claims := jwt.ExtractClaims(c)
+	jti, _ := claims[jtiKey].(string)
+	if jti == "" {
+		c.JSON(400, gin.H{"code": 400, "message": "token has no session"})
+		return
+	}
+
+	db := data.New()
+	session, err := db.Sessions.GetSessionByJTI(jti)
+	if err != nil {
+		c.JSON(404, gin.H{"code": 404, "message": "session not found"})
+		return
+	}
+	if err := db.Sessions.RevokeSession(session.ID); err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not revoke session"})
+		return
+	}
+	c.JSON(200, gin.H{"code": 200, "message": "logged out"})
+}
+
+// listUserSessionsHandler is an admin endpoint returning every session
+// belonging to :id (a username).
+func listUserSessionsHandler(c *gin.Context) {
+	username := c.Param("id")
+	sessions, err := data.New().Sessions.ListSessionsForUser(username)
+	if err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not list sessions"})
+		return
+	}
+	c.JSON(200, sessions)
+}
+
+// revokeUserSessionsHandler is an admin endpoint that revokes every session
+// belonging to :id (a username), immediately kicking that user out.
+func revokeUserSessionsHandler(c *gin.Context) {
+	username := c.Param("id")
+	if err := data.New().Sessions.RevokeSessionsForUser(username); err != nil {
+		c.JSON(500, gin.H{"code": 500, "message": "could not revoke sessions"})
+		return
+	}
+	c.JSON(200, gin.H{"code": 200, "message": "sessions revoked"})
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"github.com/alfg/openencoder/api/types"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes wires every route this package exposes onto r. Called once
+// at startup.
+func RegisterRoutes(r *gin.Engine) {
+	initOAuthProviders()
+	initMachineProviders()
+	authMiddleware := jwtMiddleware()
+
+	auth := r.Group("/auth")
+	{
+		auth.POST("/login", authMiddleware.LoginHandler)
+		auth.GET("/oauth/:provider", oauthRedirectHandler)
+		auth.GET("/oauth/:provider/callback", oauthCallbackHandler(authMiddleware))
+		auth.POST("/refresh", refreshHandler(authMiddleware))
+		auth.POST("/logout", authMiddleware.MiddlewareFunc(), logoutHandler)
+		auth.POST("/password/reset", resetPasswordHandler(authMiddleware))
+	}
+
+	users := r.Group("/users")
+	users.Use(authMiddleware.MiddlewareFunc())
+	{
+		users.GET("/:id/sessions", requireAdmin, listUserSessionsHandler)
+		users.DELETE("/:id/sessions", requireAdmin, revokeUserSessionsHandler)
+	}
+
+	apiKeys := r.Group("/api/keys")
+	apiKeys.Use(authRequired(authMiddleware))
+	{
+		apiKeys.POST("", createAPIKeyHandler)
+		apiKeys.GET("", listAPIKeysHandler)
+		apiKeys.DELETE("/:id", revokeAPIKeyHandler)
+	}
+
+	machines := r.Group("/machines")
+	machines.Use(authRequired(authMiddleware), requireAdmin, requireScope(types.ScopeMachinesManage))
+	{
+		machines.POST("", createMachineHandler)
+		machines.GET("/:provider", listMachinesHandler)
+		machines.DELETE("/:provider/:id", deleteMachineHandler)
+	}
+}
+
+// requireAdmin rejects the request unless the authenticated caller is an
+// admin. Mirrors isAdmin, but as route middleware rather than an in-handler
+// check, for routes that are admin-only end to end.
+func requireAdmin(c *gin.Context) {
+	user, ok := c.Get(identityKey)
+	if !ok || !isAdmin(user) {
+		c.AbortWithStatusJSON(403, gin.H{"code": 403, "message": "admin role required"})
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfg/openencoder/api/data"
+	"github.com/alfg/openencoder/api/helpers"
+	"github.com/alfg/openencoder/api/types"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// refreshTokenTTL is how long a refresh token (and the session it backs)
+// stays valid for after login.
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// startSession opens a new data.Sessions row for user and returns the
+// sessionUser to mint an access token for, plus the opaque refresh token to
+// hand back to the client. The refresh token is "<session id>.<secret>";
+// only a bcrypt hash of the secret is persisted.
+func startSession(c *gin.Context, user *types.User) (*sessionUser, string, error) {
+	jti := hex.EncodeToString(helpers.GenerateRandomKey(16))
+	secret := hex.EncodeToString(helpers.GenerateRandomKey(32))
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &types.Session{
+		Username:   user.Username,
+		JTI:        jti,
+		SecretHash: string(hash),
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
+		IssuedAt:   now,
+		ExpiresAt:  now.Add(refreshTokenTTL),
+	}
+	if err := data.New().Sessions.CreateSession(session); err != nil {
+		return nil, "", err
+	}
+
+	refreshToken := fmt.Sprintf("%d.%s", session.ID, secret)
+	return &sessionUser{User: user, JTI: jti}, refreshToken, nil
+}
+
+// resolveRefreshToken parses a "<session id>.<secret>" refresh token,
+// verifies the secret against the stored hash and checks that the session
+// is neither revoked nor expired.
+func resolveRefreshToken(raw string) (*types.Session, error) {
+	parts := strings.SplitN(raw, ".", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("malformed refresh token")
+	}
+
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, errors.New("malformed refresh token")
+	}
+
+	session, err := data.New().Sessions.GetSessionByID(id)
+	if err != nil {
+		return nil, errors.New("unknown refresh token")
+	}
+	if session.Revoked {
+		return nil, errors.New("session revoked")
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, errors.New("refresh token expired")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(session.SecretHash), []byte(parts[1])); err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	return session, nil
+}
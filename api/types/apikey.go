@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// APIKey is a machine-to-machine credential for CI systems and scripts that
+// submit encode jobs without an interactive login. The secret is never
+// persisted or returned after creation - only its bcrypt hash is stored.
+type APIKey struct {
+	ID         string     `json:"id" db:"id"`
+	SecretHash string     `json:"-" db:"secret_hash"`
+	Owner      string     `json:"owner" db:"owner"`
+	Role       string     `json:"role" db:"role"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// API key scopes. A route group that should be restricted to keys carrying
+// one of these adds server.requireScope(scope) to its middleware chain -
+// see the /machines group in routes.go, gated on ScopeMachinesManage. Job
+// creation/read routes don't exist yet, so ScopeJobsCreate/ScopeJobsRead
+// aren't enforced anywhere until they do.
+const (
+	ScopeJobsCreate     = "jobs:create"
+	ScopeJobsRead       = "jobs:read"
+	ScopeMachinesManage = "machines:manage"
+)
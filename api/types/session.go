@@ -0,0 +1,19 @@
+package types
+
+import "time"
+
+// Session is a persisted, revocable login session. It backs a refresh token
+// (identified by ID, authenticated by SecretHash) and the JTI of every
+// access JWT minted under it, so a compromised user can be logged out
+// immediately instead of waiting for their access token to expire.
+type Session struct {
+	ID         int64     `json:"id" db:"id"`
+	Username   string    `json:"username" db:"username"`
+	JTI        string    `json:"jti" db:"jti"`
+	SecretHash string    `json:"-" db:"secret_hash"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	IP         string    `json:"ip" db:"ip"`
+	IssuedAt   time.Time `json:"issued_at" db:"issued_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	Revoked    bool      `json:"revoked" db:"revoked"`
+}
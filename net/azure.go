@@ -0,0 +1,77 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/alfg/enc/types"
+	"github.com/alfg/openencoder/api/config"
+)
+
+func init() {
+	Register("azure", func() Backend { return &AzureBackend{} })
+}
+
+// AzureBackend downloads/uploads job files from/to Azure Blob Storage.
+type AzureBackend struct{}
+
+func (b *AzureBackend) Download(job types.Job, source Location) error {
+	blobURL, err := azureBlobURL(job.Source.Bucket, job.Source.Key)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(job.LocalSource)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx := context.Background()
+	resp, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return err
+	}
+	body := resp.Body(azblob.RetryReaderOptions{})
+	defer body.Close()
+
+	_, err = io.Copy(f, body)
+	return err
+}
+
+func (b *AzureBackend) Upload(job types.Job, dest Location) error {
+	blobURL, err := azureBlobURL(job.Dest.Bucket, job.Dest.Key)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(job.LocalDest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = azblob.UploadFileToBlockBlob(context.Background(), f, blobURL, azblob.UploadToBlockBlobOptions{})
+	return err
+}
+
+// azureBlobURL builds a block blob URL for container/key using the
+// configured storage account credentials.
+func azureBlobURL(container, key string) (azblob.BlockBlobURL, error) {
+	c := config.Get().Azure
+	credential, err := azblob.NewSharedKeyCredential(c.AccountName, c.AccountKey)
+	if err != nil {
+		return azblob.BlockBlobURL{}, err
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	u, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", c.AccountName, container, key))
+	if err != nil {
+		return azblob.BlockBlobURL{}, err
+	}
+	return azblob.NewBlockBlobURL(*u, pipeline), nil
+}
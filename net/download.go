@@ -1,11 +1,88 @@
 package net
 
-import "github.com/alfg/enc/types"
+import (
+	"fmt"
+
+	"github.com/alfg/enc/types"
+	"github.com/alfg/openencoder/api/config"
+)
+
+// Location tells GetDownloadFunc/GetUploadFunc which backend to dispatch a
+// job's source/destination to, plus the backend-specific fields a file
+// path or URL rather than a bucket/key doesn't have anywhere else to live.
+// It's threaded as its own argument instead of added to job.Source/
+// job.Dest because types.Job comes from github.com/alfg/enc/types, a
+// separate module this package doesn't own and can't add fields to -
+// unlike Bucket/Key, which already exist there for the S3 backend this
+// package used exclusively before Backend existed.
+type Location struct {
+	Provider string
+	URL      string // read by the http backend
+	Path     string // read by the local backend
+}
 
 // DownloadFunc creates a download.
-type DownloadFunc func(job types.Job) error
+type DownloadFunc func(job types.Job, source Location) error
+
+// UploadFunc creates an upload.
+type UploadFunc func(job types.Job, dest Location) error
+
+// Backend is a download/upload destination, e.g. S3, GCS, Azure Blob, an
+// HTTP(S) URL or the local filesystem.
+type Backend interface {
+	Download(job types.Job, source Location) error
+	Upload(job types.Job, dest Location) error
+}
+
+// backendFactory constructs a Backend on demand so credentials are only
+// resolved from config when a backend is actually used.
+type backendFactory func() Backend
 
-// GetDownloadFunc sets the download function.
+var backends = map[string]backendFactory{}
+
+// Register adds a backend under name for GetDownloadFunc/GetUploadFunc to
+// dispatch to. Called from each backend's init().
+func Register(name string, factory backendFactory) {
+	backends[name] = factory
+}
+
+// GetDownloadFunc returns a DownloadFunc that dispatches to the backend
+// declared by source.Provider, falling back to the configured default
+// provider when the caller doesn't declare one.
 func GetDownloadFunc() DownloadFunc {
-	return S3Download
-}
\ No newline at end of file
+	return func(job types.Job, source Location) error {
+		backend, err := resolveBackend(source.Provider)
+		if err != nil {
+			return err
+		}
+		return backend.Download(job, source)
+	}
+}
+
+// GetUploadFunc returns an UploadFunc that dispatches to the backend
+// declared by dest.Provider, falling back to the configured default
+// provider when the caller doesn't declare one.
+func GetUploadFunc() UploadFunc {
+	return func(job types.Job, dest Location) error {
+		backend, err := resolveBackend(dest.Provider)
+		if err != nil {
+			return err
+		}
+		return backend.Upload(job, dest)
+	}
+}
+
+// resolveBackend looks up the backend registered under name, falling back
+// to config.Get().DefaultStorageProvider when name is empty so existing
+// jobs that don't declare a provider keep working unchanged.
+func resolveBackend(name string) (Backend, error) {
+	if name == "" {
+		name = config.Get().DefaultStorageProvider
+	}
+
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("net: unknown backend provider %q", name)
+	}
+	return factory(), nil
+}
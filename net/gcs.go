@@ -0,0 +1,71 @@
+package net
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"cloud.google.com/go/storage"
+	"github.com/alfg/enc/types"
+	"github.com/alfg/openencoder/api/config"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register("gcs", func() Backend { return &GCSBackend{} })
+}
+
+// GCSBackend downloads/uploads job files from/to Google Cloud Storage.
+type GCSBackend struct{}
+
+func (b *GCSBackend) Download(job types.Job, source Location) error {
+	client, err := gcsClient(context.Background())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	r, err := client.Bucket(job.Source.Bucket).Object(job.Source.Key).NewReader(context.Background())
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(job.LocalSource)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func (b *GCSBackend) Upload(job types.Job, dest Location) error {
+	client, err := gcsClient(context.Background())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	f, err := os.Open(job.LocalDest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := client.Bucket(job.Dest.Bucket).Object(job.Dest.Key).NewWriter(context.Background())
+	if _, err := io.Copy(w, f); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// gcsClient builds a storage client using the credentials file configured
+// for GCS, if any, falling back to the environment default.
+func gcsClient(ctx context.Context) (*storage.Client, error) {
+	if creds := config.Get().GCS.CredentialsFile; creds != "" {
+		return storage.NewClient(ctx, option.WithCredentialsFile(creds))
+	}
+	return storage.NewClient(ctx)
+}
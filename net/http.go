@@ -0,0 +1,43 @@
+package net
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/alfg/enc/types"
+)
+
+func init() {
+	Register("http", func() Backend { return &HTTPBackend{} })
+}
+
+// HTTPBackend downloads a job source from a plain HTTP(S) URL. It has no
+// meaningful upload direction - jobs can't declare "http" as a destination.
+type HTTPBackend struct{}
+
+func (b *HTTPBackend) Download(job types.Job, source Location) error {
+	resp, err := http.Get(source.URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("net: http download failed with status " + resp.Status)
+	}
+
+	f, err := os.Create(job.LocalSource)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+func (b *HTTPBackend) Upload(job types.Job, dest Location) error {
+	return errors.New("net: http backend does not support uploads")
+}
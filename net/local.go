@@ -0,0 +1,47 @@
+package net
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alfg/enc/types"
+)
+
+func init() {
+	Register("local", func() Backend { return &LocalBackend{} })
+}
+
+// LocalBackend copies job files to/from a path on the local filesystem,
+// for single-machine or test deployments that don't want any cloud
+// dependency at all.
+type LocalBackend struct{}
+
+func (b *LocalBackend) Download(job types.Job, source Location) error {
+	return copyFile(source.Path, job.LocalSource)
+}
+
+func (b *LocalBackend) Upload(job types.Job, dest Location) error {
+	return copyFile(job.LocalDest, dest.Path)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
@@ -0,0 +1,50 @@
+package net
+
+import (
+	"os"
+
+	"github.com/alfg/enc/types"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func init() {
+	Register("s3", func() Backend { return &S3Backend{} })
+}
+
+// S3Backend adapts the existing S3Download function, and the new S3Upload
+// below, to the Backend interface.
+type S3Backend struct{}
+
+// Download fetches job.Source from S3.
+func (b *S3Backend) Download(job types.Job, source Location) error {
+	return S3Download(job)
+}
+
+// Upload sends job.Dest to S3.
+func (b *S3Backend) Upload(job types.Job, dest Location) error {
+	return S3Upload(job)
+}
+
+// S3Upload uploads job.LocalDest to job.Dest in S3. There was no upload
+// direction before this package grew Backend.Upload - S3Download only
+// ever needed to pull a job's source file down.
+func S3Upload(job types.Job) error {
+	f, err := os.Open(job.LocalDest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sess, err := session.NewSession()
+	if err != nil {
+		return err
+	}
+
+	_, err = s3manager.NewUploader(sess).Upload(&s3manager.UploadInput{
+		Bucket: &job.Dest.Bucket,
+		Key:    &job.Dest.Key,
+		Body:   f,
+	})
+	return err
+}